@@ -0,0 +1,78 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/plugindashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// GetPendingPluginDashboardChanges handles GET
+// /api/plugins/:pluginId/dashboards/pending, returning the adds/updates/
+// deletes an upgrade of this plugin would make without applying any of them.
+func (hs *HTTPServer) GetPendingPluginDashboardChanges(c *models.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	changeSet, err := hs.dashboardUpdater.ComputePendingDashboardChanges(c.Req.Context(), pluginID, c.OrgId)
+	if err != nil {
+		return response.Error(500, "Failed to compute pending dashboard changes", err)
+	}
+
+	return response.JSON(200, changeSet)
+}
+
+// ApplyPluginDashboardChanges handles POST
+// /api/plugins/:pluginId/dashboards/apply, applying a previously reviewed
+// change set as the signed-in user rather than the synthetic admin used by
+// auto-sync.
+func (hs *HTTPServer) ApplyPluginDashboardChanges(c *models.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	cmd := plugindashboards.ApplyDashboardChangesCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(400, "Bad request data", err)
+	}
+	cmd.PluginID = pluginID
+	cmd.OrgID = c.OrgId
+
+	if err := hs.dashboardUpdater.ApplyDashboardChanges(c.Req.Context(), &cmd, c.SignedInUser); err != nil {
+		return response.Error(500, "Failed to apply dashboard changes", err)
+	}
+
+	return response.Success("Dashboard changes applied")
+}
+
+// GetPluginDashboardUpdates handles GET
+// /api/plugins/:pluginId/dashboards/:uid/updates, returning the audit trail
+// of auto-updates applied to a single plugin dashboard so operators can see
+// what a version bump actually changed.
+func (hs *HTTPServer) GetPluginDashboardUpdates(c *models.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+	dashboardUID := web.Params(c.Req)[":uid"]
+
+	updates, err := hs.updateLogStore.ListPluginDashboardUpdates(c.Req.Context(), pluginID, dashboardUID, c.OrgId)
+	if err != nil {
+		return response.Error(500, "Failed to list plugin dashboard updates", err)
+	}
+
+	return response.JSON(200, updates)
+}
+
+// RollbackPluginDashboards handles POST
+// /api/plugins/:pluginId/dashboards/rollback, re-importing the dashboard
+// snapshots saved for an earlier plugin version and restoring the plugin
+// setting to that version.
+func (hs *HTTPServer) RollbackPluginDashboards(c *models.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	cmd := plugindashboards.RollbackDashboardsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(400, "Bad request data", err)
+	}
+
+	if err := hs.dashboardUpdater.RollbackPluginDashboards(c.Req.Context(), pluginID, c.OrgId, cmd.ToVersion); err != nil {
+		return response.Error(500, "Failed to roll back plugin dashboards", err)
+	}
+
+	return response.Success("Plugin dashboards rolled back")
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/plugindashboards"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	rollbackPluginDashboardsURL = "/api/plugins/test-app/dashboards/rollback"
+	pendingPluginDashboardsURL  = "/api/plugins/test-app/dashboards/pending"
+	applyPluginDashboardsURL    = "/api/plugins/test-app/dashboards/apply"
+	pluginDashboardUpdatesURL   = "/api/plugins/test-app/dashboards/dash-uid/updates"
+
+	testRollbackPluginDashboardsCmd = `{"toVersion": "1.0.0"}`
+	testApplyPluginDashboardsCmd    = `{"dashboardUids": ["dash-uid"]}`
+)
+
+func TestAPIEndpoint_GetPendingPluginDashboardChanges_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot view pending plugin dashboard changes", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, pendingPluginDashboardsURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can view pending plugin dashboard changes", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, pendingPluginDashboardsURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_GetPendingPluginDashboardChanges_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows viewing pending plugin dashboard changes with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: plugindashboards.ActionDashboardsReview}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodGet, pendingPluginDashboardsURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents viewing pending plugin dashboard changes with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "plugins:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodGet, pendingPluginDashboardsURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_ApplyPluginDashboardChanges_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot apply plugin dashboard changes", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, applyPluginDashboardsURL, strings.NewReader(testApplyPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can apply plugin dashboard changes", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, applyPluginDashboardsURL, strings.NewReader(testApplyPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_ApplyPluginDashboardChanges_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows applying plugin dashboard changes with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: plugindashboards.ActionDashboardsApply}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, applyPluginDashboardsURL, strings.NewReader(testApplyPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents applying plugin dashboard changes with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "plugins:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, applyPluginDashboardsURL, strings.NewReader(testApplyPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_GetPluginDashboardUpdates_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot view plugin dashboard update log", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, pluginDashboardUpdatesURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can view plugin dashboard update log", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, pluginDashboardUpdatesURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_GetPluginDashboardUpdates_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows viewing plugin dashboard update log with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: plugindashboards.ActionDashboardsReview}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodGet, pluginDashboardUpdatesURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents viewing plugin dashboard update log with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "plugins:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodGet, pluginDashboardUpdatesURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_RollbackPluginDashboards_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot roll back plugin dashboards", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, rollbackPluginDashboardsURL, strings.NewReader(testRollbackPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can roll back plugin dashboards", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, rollbackPluginDashboardsURL, strings.NewReader(testRollbackPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_RollbackPluginDashboards_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows rolling back plugin dashboards with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: plugindashboards.ActionDashboardsRollback}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, rollbackPluginDashboardsURL, strings.NewReader(testRollbackPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents rolling back plugin dashboards with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "plugins:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, rollbackPluginDashboardsURL, strings.NewReader(testRollbackPluginDashboardsCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
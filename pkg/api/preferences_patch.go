@@ -0,0 +1,61 @@
+package api
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/preferences"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// GetEffectivePreferences handles GET /api/preferences/effective, returning
+// the signed-in user's preferences after merging default, org, their
+// team(s) and their own overrides.
+func (hs *HTTPServer) GetEffectivePreferences(c *models.ReqContext) response.Response {
+	effective, err := hs.preferencesService.GetEffectivePreferences(c.Req.Context(), c.OrgId, c.UserId, c.Teams)
+	if err != nil {
+		return response.Error(500, "Failed to compute effective preferences", err)
+	}
+	return response.JSON(200, effective)
+}
+
+// PatchOrgPreferencesJSONData handles PATCH /api/org/preferences/json-data.
+func (hs *HTTPServer) PatchOrgPreferencesJSONData(c *models.ReqContext) response.Response {
+	return hs.patchPreferencesJSONData(c, preferences.LayerOrg, c.OrgId)
+}
+
+// PatchTeamPreferencesJSONData handles PATCH
+// /api/teams/:teamId/preferences/json-data, gated behind
+// preferences.ActionTeamsPreferencesWrite rather than the broader org-admin
+// permissions org preference writes require.
+func (hs *HTTPServer) PatchTeamPreferencesJSONData(c *models.ReqContext) response.Response {
+	teamID, err := getTeamIDFromRequest(c)
+	if err != nil {
+		return response.Error(400, "Team ID is invalid", err)
+	}
+	return hs.patchPreferencesJSONData(c, preferences.LayerTeam, teamID)
+}
+
+// PatchUserPreferencesJSONData handles PATCH /api/user/preferences/json-data.
+func (hs *HTTPServer) PatchUserPreferencesJSONData(c *models.ReqContext) response.Response {
+	return hs.patchPreferencesJSONData(c, preferences.LayerUser, c.UserId)
+}
+
+func (hs *HTTPServer) patchPreferencesJSONData(c *models.ReqContext, layer preferences.Layer, scopeID int64) response.Response {
+	patchDoc, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return response.Error(400, "Failed to read request body", err)
+	}
+
+	if err := hs.preferencesService.PatchJSONData(c.Req.Context(), layer, c.OrgId, scopeID, patchDoc); err != nil {
+		return response.Error(400, "Failed to apply preferences patch", err)
+	}
+
+	return response.Success("Preferences updated")
+}
+
+func getTeamIDFromRequest(c *models.ReqContext) (int64, error) {
+	return strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/preferences"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -132,3 +133,83 @@ func TestAPIEndpoint_PutJsonDataPreferences(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
 }
+
+var (
+	getEffectivePreferencesURL      = "/api/preferences/effective"
+	patchOrgJsonDataPreferencesURL  = "/api/org/preferences/json-data"
+	patchTeamJsonDataPreferencesURL = "/api/teams/1/preferences/json-data"
+	patchUserJsonDataPreferencesURL = "/api/user/preferences/json-data"
+
+	testPatchJsonDataPreferencesCmd    = `[{"op": "replace", "path": "/theme", "value": "light"}]`
+	testPatchJsonDataPreferencesCmdBad = `this is not a json patch document`
+)
+
+func TestAPIEndpoint_GetEffectivePreferences(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("A signed in user can read their effective preferences", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodGet, getEffectivePreferencesURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_PatchOrgPreferencesJsonData(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot patch org preferences", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPatch, patchOrgJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can patch org preferences", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPatch, patchOrgJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Returns 400 with a malformed patch document", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPatch, patchOrgJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmdBad), t)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestAPIEndpoint_PatchTeamPreferencesJsonData_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows patching team preferences with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: preferences.ActionTeamsPreferencesWrite}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPatch, patchTeamJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents patching team preferences with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "teams:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPatch, patchTeamJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_PatchUserPreferencesJsonData(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("A signed in user can patch their own preferences", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPatch, patchUserJsonDataPreferencesURL, strings.NewReader(testPatchJsonDataPreferencesCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
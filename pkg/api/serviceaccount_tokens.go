@@ -0,0 +1,98 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// CreateServiceAccountToken handles POST
+// /api/serviceaccounts/:serviceAccountId/tokens, minting a token scoped to a
+// subset of the service account's own permissions.
+func (hs *HTTPServer) CreateServiceAccountToken(c *models.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Service account ID is invalid", err)
+	}
+
+	cmd := serviceaccounts.NewTokenCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(400, "Bad request data", err)
+	}
+	cmd.ServiceAccountId = saID
+
+	granted := c.SignedInUser.Permissions[c.OrgId][serviceaccounts.ActionTokenCreate]
+	if err := serviceaccounts.ValidateScopes(granted, cmd.Scopes); err != nil {
+		return response.Error(403, "Requested token scopes exceed your own permissions", err)
+	}
+
+	result, err := hs.serviceAccountTokens.CreateToken(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.Error(500, "Failed to create service account token", err)
+	}
+
+	return response.JSON(200, result)
+}
+
+// ListServiceAccountTokens handles GET
+// /api/serviceaccounts/:serviceAccountId/tokens. Secrets are never included;
+// only the metadata needed to tell tokens apart and revoke the right one.
+func (hs *HTTPServer) ListServiceAccountTokens(c *models.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Service account ID is invalid", err)
+	}
+
+	tokens, err := hs.serviceAccountTokens.ListTokens(c.Req.Context(), c.OrgId, saID)
+	if err != nil {
+		return response.Error(500, "Failed to list service account tokens", err)
+	}
+
+	return response.JSON(200, tokens)
+}
+
+// RevokeServiceAccountToken handles DELETE
+// /api/serviceaccounts/:serviceAccountId/tokens/:tokenId.
+func (hs *HTTPServer) RevokeServiceAccountToken(c *models.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Service account ID is invalid", err)
+	}
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Token ID is invalid", err)
+	}
+
+	if err := hs.serviceAccountTokens.RevokeToken(c.Req.Context(), saID, tokenID); err != nil {
+		return response.Error(500, "Failed to revoke service account token", err)
+	}
+
+	return response.Success("Token revoked")
+}
+
+// RotateServiceAccountToken handles POST
+// /api/serviceaccounts/:serviceAccountId/tokens/:tokenId/rotate, returning a
+// new secret while the old one keeps working for the token's grace window.
+func (hs *HTTPServer) RotateServiceAccountToken(c *models.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Service account ID is invalid", err)
+	}
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(400, "Token ID is invalid", err)
+	}
+
+	result, err := hs.serviceAccountTokens.RotateToken(c.Req.Context(), &serviceaccounts.RotateTokenCmd{
+		ServiceAccountId: saID,
+		TokenId:          tokenID,
+	})
+	if err != nil {
+		return response.Error(500, "Failed to rotate service account token", err)
+	}
+
+	return response.JSON(200, result)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	createServiceAccountTokenURL = "/api/serviceaccounts/1/tokens"
+	listServiceAccountTokensURL  = "/api/serviceaccounts/1/tokens"
+	revokeServiceAccountTokenURL = "/api/serviceaccounts/1/tokens/1"
+	rotateServiceAccountTokenURL = "/api/serviceaccounts/1/tokens/1/rotate"
+
+	testCreateServiceAccountTokenCmd = `{"name": "integration-a", "scopes": ["dashboards:read:uid:*"]}`
+)
+
+func TestAPIEndpoint_CreateServiceAccountToken_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot create a service account token", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, createServiceAccountTokenURL,
+			strings.NewReader(testCreateServiceAccountTokenCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can create a service account token", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, createServiceAccountTokenURL,
+			strings.NewReader(testCreateServiceAccountTokenCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_CreateServiceAccountToken_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows creating a token with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{
+			{Action: serviceaccounts.ActionTokenCreate, Scope: serviceaccounts.ScopeID},
+		}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, createServiceAccountTokenURL,
+			strings.NewReader(testCreateServiceAccountTokenCmd), t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents creating a token with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "serviceaccounts:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, createServiceAccountTokenURL,
+			strings.NewReader(testCreateServiceAccountTokenCmd), t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_RevokeServiceAccountToken_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows revoking a token with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{
+			{Action: serviceaccounts.ActionTokenRevoke, Scope: serviceaccounts.ScopeTokenID},
+		}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodDelete, revokeServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents revoking a token with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "serviceaccounts:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodDelete, revokeServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPIEndpoint_RotateServiceAccountToken_LegacyAccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, false)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	setInitCtxSignedInViewer(sc.initCtx)
+	t.Run("Viewer cannot rotate a service account token", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, rotateServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	setInitCtxSignedInOrgAdmin(sc.initCtx)
+	t.Run("Org Admin can rotate a service account token", func(t *testing.T) {
+		response := callAPI(sc.server, http.MethodPost, rotateServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestAPIEndpoint_RotateServiceAccountToken_AccessControl(t *testing.T) {
+	sc := setupHTTPServer(t, true, true)
+	setInitCtxSignedInViewer(sc.initCtx)
+
+	_, err := sc.db.CreateOrgWithMember("TestOrg", testUserID)
+	require.NoError(t, err)
+
+	t.Run("AccessControl allows rotating a token with correct permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{
+			{Action: serviceaccounts.ActionTokenRotate, Scope: serviceaccounts.ScopeTokenID},
+		}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, rotateServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("AccessControl prevents rotating a token with incorrect permissions", func(t *testing.T) {
+		setAccessControlPermissions(sc.acmock, []*accesscontrol.Permission{{Action: "serviceaccounts:invalid"}}, sc.initCtx.OrgId)
+		response := callAPI(sc.server, http.MethodPost, rotateServiceAccountTokenURL, nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
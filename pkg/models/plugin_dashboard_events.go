@@ -0,0 +1,16 @@
+package models
+
+import "github.com/grafana/grafana/pkg/services/plugindashboards"
+
+// PluginDashboardUpdatedEvent is published on the bus once an app plugin's
+// bundled dashboard has been auto-updated, carrying a summary of what the
+// diff against the previously imported revision looked like.
+type PluginDashboardUpdatedEvent struct {
+	PluginId      string
+	OrgId         int64
+	DashboardUID  string
+	PluginVersion string
+	Revision      int64
+	OldRevision   int64
+	Diff          *plugindashboards.DashboardDiffSummary
+}
@@ -0,0 +1,10 @@
+package models
+
+// PluginDashboardsRolledBackEvent is published once
+// DashboardUpdater.RollbackPluginDashboards has finished re-importing a
+// plugin's dashboards at an earlier version.
+type PluginDashboardsRolledBackEvent struct {
+	PluginId   string
+	OrgId      int64
+	ToVersion  string
+}
@@ -0,0 +1,53 @@
+package plugindashboards
+
+// ChangeAction describes what ComputePendingDashboardChanges found between the
+// dashboards currently stored for a plugin and the ones it ships today.
+type ChangeAction string
+
+const (
+	ChangeActionAdd    ChangeAction = "add"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+// RequiredInput is something the applier needs to resolve before a change can
+// be applied, e.g. a datasource that the new dashboard revision references but
+// that isn't mapped in this org, or a folder the dashboard should move into.
+type RequiredInput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// PendingDashboardChange is a single add/update/delete a plugin version bump
+// would make to a dashboard, computed but not yet applied.
+type PendingDashboardChange struct {
+	PluginID       string        `json:"pluginId"`
+	DashboardUID   string        `json:"dashboardUid"`
+	Reference      string        `json:"reference"`
+	Action         ChangeAction  `json:"action"`
+	Revision       int64         `json:"revision"`
+	OldRevision    int64         `json:"oldRevision"`
+	RequiredInputs []RequiredInput `json:"requiredInputs,omitempty"`
+}
+
+// ChangeSet is the full set of pending changes for a plugin/org pair, returned
+// by ComputePendingDashboardChanges and later handed back to
+// ApplyDashboardChanges once an admin has reviewed it.
+type ChangeSet struct {
+	PluginID    string                    `json:"pluginId"`
+	OrgID       int64                     `json:"orgId"`
+	FromVersion string                    `json:"fromVersion"`
+	ToVersion   string                    `json:"toVersion"`
+	Changes     []PendingDashboardChange  `json:"changes"`
+}
+
+// ApplyDashboardChangesCommand selects which of a previously computed
+// ChangeSet's changes an admin actually wants applied. Omitting UIDs applies
+// the whole change set.
+type ApplyDashboardChangesCommand struct {
+	PluginID         string   `json:"pluginId"`
+	OrgID            int64    `json:"-"`
+	DashboardUIDs    []string `json:"dashboardUids,omitempty"`
+	DatasourceInputs map[string]string `json:"datasourceInputs,omitempty"`
+}
@@ -0,0 +1,6 @@
+package plugindashboards
+
+const (
+	ActionDashboardsReview = "plugins.dashboards:review"
+	ActionDashboardsApply  = "plugins.dashboards:apply"
+)
@@ -0,0 +1,45 @@
+package plugindashboards
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ActionDashboardsRollback gates POST
+// /api/plugins/:pluginId/dashboards/rollback.
+const ActionDashboardsRollback = "plugins.dashboards:rollback"
+
+// MaxRevisionsPerDashboard caps how many imported JSON snapshots the
+// RevisionStore keeps per plugin+dashboard for RollbackPluginDashboards,
+// oldest dropped first.
+const MaxRevisionsPerDashboard = 5
+
+// PluginDashboardRevision is one snapshot of a dashboard as it was imported
+// for a given plugin version, kept so a botched upgrade can be rolled back
+// without reinstalling the previous plugin version.
+type PluginDashboardRevision struct {
+	ID            int64            `json:"id" xorm:"pk autoincr 'id'"`
+	PluginID      string           `json:"pluginId" xorm:"plugin_id"`
+	OrgID         int64            `json:"orgId" xorm:"org_id"`
+	DashboardUID  string           `json:"dashboardUid" xorm:"dashboard_uid"`
+	PluginVersion string           `json:"pluginVersion" xorm:"plugin_version"`
+	Reference     string           `json:"reference" xorm:"reference"`
+	Dashboard     *simplejson.Json `json:"dashboard" xorm:"json 'dashboard'"`
+	Created       time.Time        `json:"created" xorm:"created"`
+}
+
+// RollbackDashboardsCommand selects which previously-installed plugin
+// version RollbackPluginDashboards should restore dashboards to.
+type RollbackDashboardsCommand struct {
+	ToVersion string `json:"toVersion"`
+}
+
+// RevisionStore keeps the last MaxRevisionsPerDashboard imported dashboard
+// snapshots per plugin+org+dashboard, keyed by the plugin version they were
+// imported under.
+type RevisionStore interface {
+	SaveRevision(ctx context.Context, revision *PluginDashboardRevision) error
+	ListRevisionsForVersion(ctx context.Context, pluginID string, orgID int64, pluginVersion string) ([]*PluginDashboardRevision, error)
+}
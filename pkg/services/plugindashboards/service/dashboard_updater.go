@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
@@ -12,28 +15,38 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
 	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideDashboardUpdater(bus bus.Bus, pluginStore plugins.Store, pluginDashboardService plugindashboards.Service,
+func ProvideDashboardUpdater(cfg *setting.Cfg, bus bus.Bus, pluginStore plugins.Store, pluginDashboardService plugindashboards.Service,
 	dashboardImportService dashboardimport.Service, pluginSettingsService pluginsettings.Service,
-	dashboardPluginService dashboards.PluginService, dashboardService dashboards.DashboardService) *DashboardUpdater {
-	du := newDashboardUpdater(bus, pluginStore, pluginDashboardService, dashboardImportService,
-		pluginSettingsService, dashboardPluginService, dashboardService)
-	du.updateAppDashboards()
+	dashboardPluginService dashboards.PluginService, dashboardService dashboards.DashboardService,
+	sqlStore *sqlstore.SQLStore) *DashboardUpdater {
+	du := newDashboardUpdater(cfg, bus, pluginStore, pluginDashboardService, dashboardImportService,
+		pluginSettingsService, dashboardPluginService, dashboardService, newUpdateLogStore(sqlStore), newRevisionStore(sqlStore))
+	if !du.reviewModeEnabled {
+		du.updateAppDashboards()
+	}
 	return du
 }
 
-func newDashboardUpdater(bus bus.Bus, pluginStore plugins.Store,
+func newDashboardUpdater(cfg *setting.Cfg, bus bus.Bus, pluginStore plugins.Store,
 	pluginDashboardService plugindashboards.Service, dashboardImportService dashboardimport.Service,
 	pluginSettingsService pluginsettings.Service, dashboardPluginService dashboards.PluginService,
-	dashboardService dashboards.DashboardService) *DashboardUpdater {
+	dashboardService dashboards.DashboardService, updateLogStore plugindashboards.UpdateLogStore,
+	revisionStore plugindashboards.RevisionStore) *DashboardUpdater {
 	s := &DashboardUpdater{
+		bus:                    bus,
 		pluginStore:            pluginStore,
 		pluginDashboardService: pluginDashboardService,
 		dashboardImportService: dashboardImportService,
 		pluginSettingsService:  pluginSettingsService,
 		dashboardPluginService: dashboardPluginService,
 		dashboardService:       dashboardService,
+		updateLogStore:         updateLogStore,
+		revisionStore:          revisionStore,
+		reviewModeEnabled:      cfg.Raw.Section("plugins").Key("org_review_required_dashboard_updates").MustBool(false),
 		logger:                 log.New("plugindashboards"),
 	}
 	bus.AddEventListener(s.handlePluginStateChanged)
@@ -41,13 +54,26 @@ func newDashboardUpdater(bus bus.Bus, pluginStore plugins.Store,
 	return s
 }
 
+// DashboardUpdater keeps the dashboards a plugin ships in sync with the
+// dashboards Grafana has imported for it.
+//
+// By default it runs in auto-sync mode: syncPluginDashboards is called
+// whenever a plugin's version changes and overwrites the imported dashboards
+// straight away. When reviewModeEnabled is set, auto-sync is skipped and
+// callers must go through ComputePendingDashboardChanges/ApplyDashboardChanges
+// instead, so an admin can confirm an upgrade before it touches a dashboard a
+// user may have edited.
 type DashboardUpdater struct {
+	bus                    bus.Bus
 	pluginStore            plugins.Store
 	pluginDashboardService plugindashboards.Service
 	dashboardImportService dashboardimport.Service
 	pluginSettingsService  pluginsettings.Service
 	dashboardPluginService dashboards.PluginService
 	dashboardService       dashboards.DashboardService
+	updateLogStore         plugindashboards.UpdateLogStore
+	revisionStore          plugindashboards.RevisionStore
+	reviewModeEnabled      bool
 	logger                 log.Logger
 }
 
@@ -139,7 +165,11 @@ func (du *DashboardUpdater) handlePluginStateChanged(ctx context.Context, event
 			return fmt.Errorf("plugin %s not found. Could not sync plugin dashboards", event.PluginId)
 		}
 
-		du.syncPluginDashboards(ctx, p, event.OrgId)
+		if du.reviewModeEnabled {
+			du.logger.Info("Skipping plugin dashboard auto-sync, org is in review mode", "pluginId", event.PluginId)
+		} else {
+			du.syncPluginDashboards(ctx, p, event.OrgId)
+		}
 	} else {
 		query := models.GetDashboardsByPluginIdQuery{PluginId: event.PluginId, OrgId: event.OrgId}
 		if err := du.dashboardPluginService.GetDashboardsByPluginID(ctx, &query); err != nil {
@@ -168,6 +198,9 @@ func (du *DashboardUpdater) autoUpdateAppDashboard(ctx context.Context, pluginDa
 	}
 	du.logger.Info("Auto updating App dashboard", "dashboard", resp.Dashboard.Title, "newRev",
 		pluginDashInfo.Revision, "oldRev", pluginDashInfo.ImportedRevision)
+
+	diffSummary, diffHTML := du.diffAgainstImported(ctx, pluginDashInfo, resp.Dashboard.Data, orgID)
+
 	_, err = du.dashboardImportService.ImportDashboard(ctx, &dashboardimport.ImportDashboardRequest{
 		PluginId:  pluginDashInfo.PluginId,
 		User:      &models.SignedInUser{UserId: 0, OrgRole: models.ROLE_ADMIN, OrgId: orgID},
@@ -177,5 +210,413 @@ func (du *DashboardUpdater) autoUpdateAppDashboard(ctx context.Context, pluginDa
 		Overwrite: true,
 		Inputs:    nil,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	pluginVersion := ""
+	if plugin, exists := du.pluginStore.Plugin(ctx, pluginDashInfo.PluginId); exists {
+		pluginVersion = plugin.Info.Version
+	}
+
+	update := &plugindashboards.PluginDashboardUpdate{
+		PluginID:      pluginDashInfo.PluginId,
+		OrgID:         orgID,
+		DashboardUID:  pluginDashInfo.UID,
+		PluginVersion: pluginVersion,
+		Revision:      pluginDashInfo.Revision,
+		Diff:          diffSummary,
+		DiffHTML:      diffHTML,
+	}
+	if err := du.updateLogStore.InsertPluginDashboardUpdate(ctx, update); err != nil {
+		du.logger.Error("Failed to persist plugin dashboard update log", "pluginId", pluginDashInfo.PluginId, "error", err)
+	}
+
+	if pluginVersion != "" {
+		revision := &plugindashboards.PluginDashboardRevision{
+			PluginID:      pluginDashInfo.PluginId,
+			OrgID:         orgID,
+			DashboardUID:  pluginDashInfo.UID,
+			PluginVersion: pluginVersion,
+			Reference:     pluginDashInfo.Reference,
+			Dashboard:     resp.Dashboard.Data,
+		}
+		if err := du.revisionStore.SaveRevision(ctx, revision); err != nil {
+			du.logger.Error("Failed to save plugin dashboard revision", "pluginId", pluginDashInfo.PluginId, "error", err)
+		}
+	}
+
+	du.bus.Publish(ctx, &models.PluginDashboardUpdatedEvent{
+		PluginId:      pluginDashInfo.PluginId,
+		OrgId:         orgID,
+		DashboardUID:  pluginDashInfo.UID,
+		PluginVersion: pluginVersion,
+		Revision:      pluginDashInfo.Revision,
+		OldRevision:   pluginDashInfo.ImportedRevision,
+		Diff:          diffSummary,
+	})
+
+	return nil
+}
+
+// diffAgainstImported compares the dashboard currently imported for
+// pluginDashInfo against the new plugin-provided JSON, using dashdiffs for
+// the rendered HTML delta and a lightweight JSON walk for the structured
+// summary persisted in the audit log.
+func (du *DashboardUpdater) diffAgainstImported(ctx context.Context, pluginDashInfo *plugindashboards.PluginDashboard, newDashboard *simplejson.Json, orgID int64) (*plugindashboards.DashboardDiffSummary, string) {
+	query := models.GetDashboardQuery{Id: pluginDashInfo.DashboardId, OrgId: orgID}
+	if err := du.dashboardService.GetDashboard(ctx, &query); err != nil {
+		du.logger.Warn("Failed to load currently imported dashboard for diff", "pluginId", pluginDashInfo.PluginId, "error", err)
+		return nil, ""
+	}
+	oldDashboard := query.Result.Data
+
+	result, err := dashdiffs.CalculateDiff(ctx, &dashdiffs.Options{
+		OrgId:    orgID,
+		DiffType: dashdiffs.DiffJSON,
+		Left:     dashdiffs.DiffTarget{UnsavedDashboard: &models.Dashboard{Data: oldDashboard}},
+		Right:    dashdiffs.DiffTarget{UnsavedDashboard: &models.Dashboard{Data: newDashboard}},
+	})
+	if err != nil {
+		du.logger.Warn("Failed to calculate plugin dashboard diff", "pluginId", pluginDashInfo.PluginId, "error", err)
+		return nil, ""
+	}
+
+	return summarizeDashboardDiff(oldDashboard, newDashboard), string(result.Delta)
+}
+
+// summarizeDashboardDiff walks the old and new dashboard JSON and reports
+// which panels changed, which template variables were added or removed, and
+// which datasource inputs were remapped, independent of the rendered HTML
+// diff. Template variables are compared by name only.
+func summarizeDashboardDiff(old, new *simplejson.Json) *plugindashboards.DashboardDiffSummary {
+	summary := &plugindashboards.DashboardDiffSummary{}
+
+	oldPanels := panelsByID(old)
+	newPanels := panelsByID(new)
+	remaps := map[string]string{}
+	for id, newPanel := range newPanels {
+		oldPanel, ok := oldPanels[id]
+		if !ok {
+			summary.PanelsAdded = append(summary.PanelsAdded, newPanel.title)
+			continue
+		}
+		if oldPanel.title != newPanel.title || oldPanel.panelType != newPanel.panelType ||
+			oldPanel.datasource != newPanel.datasource || oldPanel.targets != newPanel.targets {
+			summary.PanelsChanged = append(summary.PanelsChanged, newPanel.title)
+		}
+		if oldPanel.datasource != "" && newPanel.datasource != "" && oldPanel.datasource != newPanel.datasource {
+			remaps[oldPanel.datasource] = newPanel.datasource
+		}
+	}
+	for id, oldPanel := range oldPanels {
+		if _, ok := newPanels[id]; !ok {
+			summary.PanelsRemoved = append(summary.PanelsRemoved, oldPanel.title)
+		}
+	}
+	if len(remaps) > 0 {
+		summary.DatasourceRemaps = remaps
+	}
+
+	oldVars := templateVarNames(old)
+	newVars := templateVarNames(new)
+	for name := range newVars {
+		if _, ok := oldVars[name]; !ok {
+			summary.VariablesChanged = append(summary.VariablesChanged, name)
+		}
+	}
+	for name := range oldVars {
+		if _, ok := newVars[name]; !ok {
+			summary.VariablesChanged = append(summary.VariablesChanged, name)
+		}
+	}
+
+	return summary
+}
+
+// panelInfo is the subset of a panel's JSON that summarizeDashboardDiff
+// compares to decide whether a panel that survived an upgrade actually
+// changed, and whether its datasource was remapped.
+type panelInfo struct {
+	title      string
+	panelType  string
+	datasource string
+	targets    string
+}
+
+func panelsByID(dash *simplejson.Json) map[int64]panelInfo {
+	panels := map[int64]panelInfo{}
+	for _, p := range dash.Get("panels").MustArray() {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := panel["id"].(json.Number)
+		if !ok {
+			continue
+		}
+		panelID, err := id.Int64()
+		if err != nil {
+			continue
+		}
+		title, _ := panel["title"].(string)
+		panelType, _ := panel["type"].(string)
+		datasource, _ := panel["datasource"].(string)
+		targets, _ := json.Marshal(panel["targets"])
+		panels[panelID] = panelInfo{title: title, panelType: panelType, datasource: datasource, targets: string(targets)}
+	}
+	return panels
+}
+
+func templateVarNames(dash *simplejson.Json) map[string]bool {
+	names := map[string]bool{}
+	for _, v := range dash.Get("templating").Get("list").MustArray() {
+		tmpl, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := tmpl["name"].(string); ok {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// ComputePendingDashboardChanges diffs the dashboards currently imported for
+// a plugin against the ones the installed plugin version ships, without
+// changing anything. The result is what GET
+// /api/plugins/:pluginId/dashboards/pending returns for an admin to review.
+func (du *DashboardUpdater) ComputePendingDashboardChanges(ctx context.Context, pluginID string, orgID int64) (*plugindashboards.ChangeSet, error) {
+	plugin, exists := du.pluginStore.Plugin(ctx, pluginID)
+	if !exists {
+		return nil, fmt.Errorf("plugin %s not found", pluginID)
+	}
+
+	req := &plugindashboards.ListPluginDashboardsRequest{OrgID: orgID, PluginID: pluginID}
+	resp, err := du.pluginDashboardService.ListPluginDashboards(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := &plugindashboards.ChangeSet{
+		PluginID:  pluginID,
+		OrgID:     orgID,
+		ToVersion: plugin.Info.Version,
+	}
+
+	for _, dash := range resp.Items {
+		switch {
+		case dash.Removed:
+			changeSet.Changes = append(changeSet.Changes, plugindashboards.PendingDashboardChange{
+				PluginID:     pluginID,
+				DashboardUID: dash.UID,
+				Reference:    dash.Reference,
+				Action:       plugindashboards.ChangeActionDelete,
+				OldRevision:  dash.ImportedRevision,
+			})
+		case dash.ImportedRevision != dash.Revision:
+			action := plugindashboards.ChangeActionUpdate
+			if dash.ImportedRevision == 0 {
+				action = plugindashboards.ChangeActionAdd
+			}
+			changeSet.Changes = append(changeSet.Changes, plugindashboards.PendingDashboardChange{
+				PluginID:       pluginID,
+				DashboardUID:   dash.UID,
+				Reference:      dash.Reference,
+				Action:         action,
+				Revision:       dash.Revision,
+				OldRevision:    dash.ImportedRevision,
+				RequiredInputs: du.requiredInputsFor(ctx, dash, orgID),
+			})
+		}
+	}
+
+	return changeSet, nil
+}
+
+// requiredInputsFor reports the inputs (such as datasource mappings) an admin
+// needs to resolve before a dashboard change can be applied, by inspecting
+// the plugin-provided dashboard's own __inputs section. Folder moves are not
+// detected.
+func (du *DashboardUpdater) requiredInputsFor(ctx context.Context, dash *plugindashboards.PluginDashboard, orgID int64) []plugindashboards.RequiredInput {
+	req := &plugindashboards.LoadPluginDashboardRequest{PluginID: dash.PluginId, Reference: dash.Reference}
+	resp, err := du.pluginDashboardService.LoadPluginDashboard(ctx, req)
+	if err != nil {
+		du.logger.Warn("Failed to inspect plugin dashboard for required inputs", "pluginId", dash.PluginId, "error", err)
+		return nil
+	}
+
+	inputs := resp.Dashboard.Data.Get("__inputs").MustArray()
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	out := make([]plugindashboards.RequiredInput, 0, len(inputs))
+	for _, raw := range inputs {
+		in, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		out = append(out, plugindashboards.RequiredInput{
+			Name:  fmt.Sprintf("%v", in["name"]),
+			Type:  fmt.Sprintf("%v", in["type"]),
+			Label: fmt.Sprintf("%v", in["label"]),
+		})
+	}
+	return out
+}
+
+// datasourceImportInputs turns the flat name->datasource-uid map an admin
+// submits for a ChangeSet's RequiredInputs into the Inputs
+// dashboardimport.ImportDashboard expects, so a dashboard that templates a
+// datasource actually gets it resolved instead of importing with the
+// placeholder left in.
+func datasourceImportInputs(datasourceInputs map[string]string) []dashboardimport.ImportDashboardInput {
+	if len(datasourceInputs) == 0 {
+		return nil
+	}
+
+	inputs := make([]dashboardimport.ImportDashboardInput, 0, len(datasourceInputs))
+	for name, value := range datasourceInputs {
+		inputs = append(inputs, dashboardimport.ImportDashboardInput{
+			Name:  name,
+			Type:  "datasource",
+			Value: value,
+		})
+	}
+	return inputs
+}
+
+// ApplyDashboardChanges applies a change set an admin has reviewed via
+// ComputePendingDashboardChanges, using the supplied user's identity rather
+// than the synthetic admin syncPluginDashboards uses for auto-sync, and
+// records the plugin as up to date once every change has been applied.
+func (du *DashboardUpdater) ApplyDashboardChanges(ctx context.Context, cmd *plugindashboards.ApplyDashboardChangesCommand, user *models.SignedInUser) error {
+	changeSet, err := du.ComputePendingDashboardChanges(ctx, cmd.PluginID, cmd.OrgID)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, uid := range cmd.DashboardUIDs {
+		wanted[uid] = true
+	}
+
+	req := &plugindashboards.ListPluginDashboardsRequest{OrgID: cmd.OrgID, PluginID: cmd.PluginID}
+	resp, err := du.pluginDashboardService.ListPluginDashboards(ctx, req)
+	if err != nil {
+		return err
+	}
+	byUID := map[string]*plugindashboards.PluginDashboard{}
+	for _, dash := range resp.Items {
+		byUID[dash.UID] = dash
+	}
+
+	skipped := false
+	for _, change := range changeSet.Changes {
+		if len(wanted) > 0 && !wanted[change.DashboardUID] {
+			skipped = true
+			continue
+		}
+
+		dash, ok := byUID[change.DashboardUID]
+		if !ok {
+			continue
+		}
+
+		if change.Action == plugindashboards.ChangeActionDelete {
+			if err := du.dashboardService.DeleteDashboard(ctx, dash.DashboardId, cmd.OrgID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pluginReq := &plugindashboards.LoadPluginDashboardRequest{PluginID: dash.PluginId, Reference: dash.Reference}
+		pluginResp, err := du.pluginDashboardService.LoadPluginDashboard(ctx, pluginReq)
+		if err != nil {
+			return err
+		}
+		_, err = du.dashboardImportService.ImportDashboard(ctx, &dashboardimport.ImportDashboardRequest{
+			PluginId:  dash.PluginId,
+			User:      user,
+			Path:      dash.Reference,
+			FolderId:  0,
+			Dashboard: pluginResp.Dashboard.Data,
+			Overwrite: true,
+			Inputs:    datasourceImportInputs(cmd.DatasourceInputs),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if skipped {
+		// Only some of the reviewed changes were applied, so the plugin isn't
+		// fully caught up to ToVersion yet - leave plugin_setting alone so the
+		// next ComputePendingDashboardChanges still reports what's left.
+		return nil
+	}
+
+	query := models.GetPluginSettingByIdQuery{PluginId: cmd.PluginID, OrgId: cmd.OrgID}
+	if err := du.pluginSettingsService.GetPluginSettingById(ctx, &query); err != nil {
+		return err
+	}
+	return du.pluginSettingsService.UpdatePluginSettingVersion(ctx, &models.UpdatePluginSettingVersionCmd{
+		OrgId:         query.Result.OrgId,
+		PluginId:      query.Result.PluginId,
+		PluginVersion: changeSet.ToVersion,
+	})
+}
+
+// RollbackPluginDashboards undoes a plugin dashboard upgrade by re-importing
+// the snapshots saved under toVersion and restoring plugin_setting to that
+// version, for when an upgrade turns out to be botched and the previous
+// plugin version is no longer installed to reinstall instead.
+func (du *DashboardUpdater) RollbackPluginDashboards(ctx context.Context, pluginID string, orgID int64, toVersion string) error {
+	revisions, err := du.revisionStore.ListRevisionsForVersion(ctx, pluginID, orgID, toVersion)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return fmt.Errorf("no plugin dashboard revisions found for %s at version %s", pluginID, toVersion)
+	}
+
+	seen := map[string]bool{}
+	for _, revision := range revisions {
+		if seen[revision.DashboardUID] {
+			continue
+		}
+		seen[revision.DashboardUID] = true
+
+		_, err := du.dashboardImportService.ImportDashboard(ctx, &dashboardimport.ImportDashboardRequest{
+			PluginId:  pluginID,
+			User:      &models.SignedInUser{UserId: 0, OrgRole: models.ROLE_ADMIN, OrgId: orgID},
+			Path:      revision.Reference,
+			Dashboard: revision.Dashboard,
+			Overwrite: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back dashboard %s: %w", revision.DashboardUID, err)
+		}
+	}
+
+	query := models.GetPluginSettingByIdQuery{PluginId: pluginID, OrgId: orgID}
+	if err := du.pluginSettingsService.GetPluginSettingById(ctx, &query); err != nil {
+		return err
+	}
+	if err := du.pluginSettingsService.UpdatePluginSettingVersion(ctx, &models.UpdatePluginSettingVersionCmd{
+		OrgId:         query.Result.OrgId,
+		PluginId:      query.Result.PluginId,
+		PluginVersion: toVersion,
+	}); err != nil {
+		return err
+	}
+
+	du.bus.Publish(ctx, &models.PluginDashboardsRolledBackEvent{
+		PluginId:  pluginID,
+		OrgId:     orgID,
+		ToVersion: toVersion,
+	})
+
+	return nil
 }
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanelsByID(t *testing.T) {
+	dash, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "title": "CPU", "type": "graph", "datasource": "old-ds"},
+			{"id": 2, "title": "Memory"},
+			{"id": "not-a-number", "title": "Ignored"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	panels := panelsByID(dash)
+	require.Len(t, panels, 2)
+	assert.Equal(t, panelInfo{title: "CPU", panelType: "graph", datasource: "old-ds", targets: "null"}, panels[1])
+	assert.Equal(t, "Memory", panels[2].title)
+}
+
+func TestSummarizeDashboardDiff(t *testing.T) {
+	old, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "title": "CPU", "type": "graph", "datasource": "old-ds"},
+			{"id": 2, "title": "Memory"}
+		],
+		"templating": {"list": [{"name": "datasource"}]}
+	}`))
+	require.NoError(t, err)
+
+	newDash, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "title": "CPU", "type": "graph", "datasource": "new-ds"},
+			{"id": 3, "title": "Disk"}
+		],
+		"templating": {"list": [{"name": "datasource"}, {"name": "region"}]}
+	}`))
+	require.NoError(t, err)
+
+	summary := summarizeDashboardDiff(old, newDash)
+	assert.ElementsMatch(t, []string{"Disk"}, summary.PanelsAdded)
+	assert.ElementsMatch(t, []string{"Memory"}, summary.PanelsRemoved)
+	assert.ElementsMatch(t, []string{"CPU"}, summary.PanelsChanged)
+	assert.ElementsMatch(t, []string{"region"}, summary.VariablesChanged)
+	assert.Equal(t, map[string]string{"old-ds": "new-ds"}, summary.DatasourceRemaps)
+}
+
+func TestSummarizeDashboardDiff_PanelContentChangeWithoutRename(t *testing.T) {
+	old, err := simplejson.NewJson([]byte(`{
+		"panels": [{"id": 1, "title": "CPU", "type": "graph", "targets": [{"expr": "rate(cpu[5m])"}]}]
+	}`))
+	require.NoError(t, err)
+
+	newDash, err := simplejson.NewJson([]byte(`{
+		"panels": [{"id": 1, "title": "CPU", "type": "graph", "targets": [{"expr": "rate(cpu[1m])"}]}]
+	}`))
+	require.NoError(t, err)
+
+	summary := summarizeDashboardDiff(old, newDash)
+	assert.Empty(t, summary.PanelsAdded)
+	assert.Empty(t, summary.PanelsRemoved)
+	assert.ElementsMatch(t, []string{"CPU"}, summary.PanelsChanged)
+}
+
+func TestSummarizeDashboardDiff_NoChanges(t *testing.T) {
+	dash, err := simplejson.NewJson([]byte(`{
+		"panels": [{"id": 1, "title": "CPU", "type": "graph", "datasource": "ds"}],
+		"templating": {"list": [{"name": "datasource"}]}
+	}`))
+	require.NoError(t, err)
+
+	summary := summarizeDashboardDiff(dash, dash)
+	assert.Empty(t, summary.PanelsAdded)
+	assert.Empty(t, summary.PanelsRemoved)
+	assert.Empty(t, summary.PanelsChanged)
+	assert.Empty(t, summary.VariablesChanged)
+	assert.Nil(t, summary.DatasourceRemaps)
+}
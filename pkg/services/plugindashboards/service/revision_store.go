@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/plugindashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type sqlRevisionStore struct {
+	sqlStore *sqlstore.SQLStore
+}
+
+func newRevisionStore(sqlStore *sqlstore.SQLStore) plugindashboards.RevisionStore {
+	return &sqlRevisionStore{sqlStore: sqlStore}
+}
+
+func (s *sqlRevisionStore) SaveRevision(ctx context.Context, revision *plugindashboards.PluginDashboardRevision) error {
+	return s.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Insert(revision); err != nil {
+			return err
+		}
+
+		var stale []*plugindashboards.PluginDashboardRevision
+		err := sess.Where("plugin_id = ? AND org_id = ? AND dashboard_uid = ?",
+			revision.PluginID, revision.OrgID, revision.DashboardUID).
+			Desc("created").
+			Find(&stale)
+		if err != nil {
+			return err
+		}
+
+		if len(stale) <= plugindashboards.MaxRevisionsPerDashboard {
+			return nil
+		}
+		for _, old := range stale[plugindashboards.MaxRevisionsPerDashboard:] {
+			if _, err := sess.ID(old.ID).Delete(&plugindashboards.PluginDashboardRevision{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sqlRevisionStore) ListRevisionsForVersion(ctx context.Context, pluginID string, orgID int64, pluginVersion string) ([]*plugindashboards.PluginDashboardRevision, error) {
+	var revisions []*plugindashboards.PluginDashboardRevision
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("plugin_id = ? AND org_id = ? AND plugin_version = ?", pluginID, orgID, pluginVersion).
+			Desc("created").
+			Find(&revisions)
+	})
+	return revisions, err
+}
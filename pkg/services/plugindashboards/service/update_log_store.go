@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/plugindashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type sqlUpdateLogStore struct {
+	sqlStore *sqlstore.SQLStore
+}
+
+func newUpdateLogStore(sqlStore *sqlstore.SQLStore) plugindashboards.UpdateLogStore {
+	return &sqlUpdateLogStore{sqlStore: sqlStore}
+}
+
+func (s *sqlUpdateLogStore) InsertPluginDashboardUpdate(ctx context.Context, update *plugindashboards.PluginDashboardUpdate) error {
+	return s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Insert(update)
+		return err
+	})
+}
+
+func (s *sqlUpdateLogStore) ListPluginDashboardUpdates(ctx context.Context, pluginID, dashboardUID string, orgID int64) ([]*plugindashboards.PluginDashboardUpdate, error) {
+	var updates []*plugindashboards.PluginDashboardUpdate
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("plugin_id = ? AND dashboard_uid = ? AND org_id = ?", pluginID, dashboardUID, orgID).
+			Desc("created").
+			Find(&updates)
+	})
+	return updates, err
+}
@@ -0,0 +1,42 @@
+package plugindashboards
+
+import (
+	"context"
+	"time"
+)
+
+// DashboardDiffSummary is the structured part of a plugin dashboard update:
+// what changed between the dashboard Grafana had imported and the one the
+// new plugin version ships, independent of the rendered HTML diff.
+type DashboardDiffSummary struct {
+	PanelsAdded      []string          `json:"panelsAdded,omitempty"`
+	PanelsRemoved    []string          `json:"panelsRemoved,omitempty"`
+	PanelsChanged    []string          `json:"panelsChanged,omitempty"`
+	VariablesChanged []string          `json:"variablesChanged,omitempty"`
+	DatasourceRemaps map[string]string `json:"datasourceRemaps,omitempty"`
+}
+
+// PluginDashboardUpdate is one row of the audit trail an auto-update writes
+// to plugin_dashboard_update_log, returned by
+// GET /api/plugins/:pluginId/dashboards/:uid/updates.
+type PluginDashboardUpdate struct {
+	ID            int64                 `json:"id" xorm:"pk autoincr 'id'"`
+	PluginID      string                `json:"pluginId" xorm:"plugin_id"`
+	OrgID         int64                 `json:"orgId" xorm:"org_id"`
+	DashboardUID  string                `json:"dashboardUid" xorm:"dashboard_uid"`
+	PluginVersion string                `json:"pluginVersion" xorm:"plugin_version"`
+	Revision      int64                 `json:"revision" xorm:"revision"`
+	Diff          *DashboardDiffSummary `json:"diff" xorm:"json 'diff'"`
+	DiffHTML      string                `json:"diffHtml,omitempty" xorm:"diff_html"`
+	Created       time.Time             `json:"created" xorm:"created"`
+}
+
+func (PluginDashboardUpdate) TableName() string {
+	return "plugin_dashboard_update_log"
+}
+
+// UpdateLogStore persists the audit trail of plugin dashboard auto-updates.
+type UpdateLogStore interface {
+	InsertPluginDashboardUpdate(ctx context.Context, update *PluginDashboardUpdate) error
+	ListPluginDashboardUpdates(ctx context.Context, pluginID, dashboardUID string, orgID int64) ([]*PluginDashboardUpdate, error)
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/preferences"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// PreferenceStore is the SQL-backed preferences.Store: one row per org,
+// team or user layer in the layered_preference table, upserted on write so
+// a layer that's never been touched just reads back as empty rather than
+// needing a row seeded up front.
+type PreferenceStore struct {
+	sqlStore *sqlstore.SQLStore
+}
+
+func ProvideStore(sqlStore *sqlstore.SQLStore) *PreferenceStore {
+	return &PreferenceStore{sqlStore: sqlStore}
+}
+
+func (s *PreferenceStore) GetOrgPreference(ctx context.Context, orgID int64) (*preferences.Preference, error) {
+	return s.get(ctx, "org_id = ? AND team_id = 0 AND user_id = 0", orgID)
+}
+
+func (s *PreferenceStore) GetTeamPreference(ctx context.Context, orgID, teamID int64) (*preferences.Preference, error) {
+	return s.get(ctx, "org_id = ? AND team_id = ? AND user_id = 0", orgID, teamID)
+}
+
+func (s *PreferenceStore) GetUserPreference(ctx context.Context, orgID, userID int64) (*preferences.Preference, error) {
+	return s.get(ctx, "org_id = ? AND team_id = 0 AND user_id = ?", orgID, userID)
+}
+
+func (s *PreferenceStore) ListTeamPreferences(ctx context.Context, orgID int64, teamIDs []int64) ([]*preferences.Preference, error) {
+	if len(teamIDs) == 0 {
+		return nil, nil
+	}
+
+	var prefs []*preferences.Preference
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ? AND user_id = 0", orgID).In("team_id", teamIDs).Find(&prefs)
+	})
+	return prefs, err
+}
+
+func (s *PreferenceStore) SetOrgPreferenceJSONData(ctx context.Context, orgID int64, jsonData *simplejson.Json) error {
+	return s.upsertJSONData(ctx, orgID, 0, 0, jsonData)
+}
+
+func (s *PreferenceStore) SetTeamPreferenceJSONData(ctx context.Context, orgID, teamID int64, jsonData *simplejson.Json) error {
+	return s.upsertJSONData(ctx, orgID, teamID, 0, jsonData)
+}
+
+func (s *PreferenceStore) SetUserPreferenceJSONData(ctx context.Context, orgID, userID int64, jsonData *simplejson.Json) error {
+	return s.upsertJSONData(ctx, orgID, 0, userID, jsonData)
+}
+
+func (s *PreferenceStore) get(ctx context.Context, cond string, args ...interface{}) (*preferences.Preference, error) {
+	var pref preferences.Preference
+	var found bool
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		has, err := sess.Where(cond, args...).Get(&pref)
+		found = has
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &pref, nil
+}
+
+func (s *PreferenceStore) upsertJSONData(ctx context.Context, orgID, teamID, userID int64, jsonData *simplejson.Json) error {
+	return s.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var existing preferences.Preference
+		has, err := sess.Where("org_id = ? AND team_id = ? AND user_id = ?", orgID, teamID, userID).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			existing.JsonData = jsonData
+			_, err := sess.ID(existing.Id).Cols("json_data").Update(&existing)
+			return err
+		}
+
+		_, err = sess.Insert(&preferences.Preference{
+			OrgId:    orgID,
+			TeamId:   teamID,
+			UserId:   userID,
+			JsonData: jsonData,
+		})
+		return err
+	})
+}
@@ -0,0 +1,146 @@
+package preferences
+
+import "github.com/grafana/grafana/pkg/components/simplejson"
+
+// scopedPreference pairs a stored Preference with the Layer it was loaded
+// from, so MergePreferences can record provenance as it folds layers
+// together.
+type scopedPreference struct {
+	layer Layer
+	pref  *Preference
+}
+
+// MergePreferences folds default -> org -> team(s) -> user preferences into
+// one EffectivePreferences value, applying the layers in that order
+// whatever the argument order. Teams may contribute more than one layer (a
+// user can be on several teams), and within the team layers the last one in
+// teams wins same as any other scope, so callers should order a user's teams
+// from least to most specific if that distinction matters to them.
+func MergePreferences(org, user *Preference, teams []*Preference) *EffectivePreferences {
+	scoped := []scopedPreference{{LayerDefault, defaultPreference()}}
+	if org != nil {
+		scoped = append(scoped, scopedPreference{LayerOrg, org})
+	}
+	for _, team := range teams {
+		if team != nil {
+			scoped = append(scoped, scopedPreference{LayerTeam, team})
+		}
+	}
+	if user != nil {
+		scoped = append(scoped, scopedPreference{LayerUser, user})
+	}
+
+	result := &EffectivePreferences{Sources: map[string]Layer{}}
+	var jsonLayers []*simplejson.Json
+
+	for _, sp := range scoped {
+		p := sp.pref
+		if p.Theme != "" {
+			result.Theme = p.Theme
+			result.Sources["theme"] = sp.layer
+		}
+		if p.Timezone != "" {
+			result.Timezone = p.Timezone
+			result.Sources["timezone"] = sp.layer
+		}
+		if p.WeekStart != "" {
+			result.WeekStart = p.WeekStart
+			result.Sources["weekStart"] = sp.layer
+		}
+		if p.HomeDashboardId != 0 {
+			result.HomeDashboardId = p.HomeDashboardId
+			result.Sources["homeDashboardId"] = sp.layer
+		}
+		if p.JsonData != nil {
+			jsonLayers = append(jsonLayers, p.JsonData)
+		}
+	}
+
+	result.JsonData = mergeJSONData(jsonLayers)
+	return result
+}
+
+func defaultPreference() *Preference {
+	return &Preference{Theme: "", Timezone: "", WeekStart: ""}
+}
+
+// mergeJSONData merges a stack of jsonData blobs key by key at the top level,
+// deepest layer wins per key; nested objects are replaced whole, not merged.
+// Arrays of objects carrying a stable "id" field (e.g. navbar
+// entries) are merged element-by-id rather than replaced wholesale, so a
+// team layer can hide one nav item without clobbering a user's other nav
+// customizations.
+func mergeJSONData(layers []*simplejson.Json) *simplejson.Json {
+	if len(layers) == 0 {
+		return simplejson.New()
+	}
+
+	merged := map[string]interface{}{}
+	for _, layer := range layers {
+		layerMap, err := layer.Map()
+		if err != nil {
+			continue
+		}
+		for key, value := range layerMap {
+			if existing, ok := merged[key]; ok {
+				if existingArr, ok := existing.([]interface{}); ok {
+					if newArr, ok := value.([]interface{}); ok {
+						merged[key] = mergeByID(existingArr, newArr)
+						continue
+					}
+				}
+			}
+			merged[key] = value
+		}
+	}
+
+	out := simplejson.New()
+	for key, value := range merged {
+		out.Set(key, value)
+	}
+	return out
+}
+
+// mergeByID merges two arrays of objects keyed by their "id" field,
+// overwriting entries with items from next that share an id and appending
+// any the base array didn't have. Entries without an "id" field are treated
+// as opaque and next wins outright.
+func mergeByID(base, next []interface{}) []interface{} {
+	hasIDs := true
+	index := map[string]int{}
+	for i, item := range base {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			hasIDs = false
+			break
+		}
+		id, ok := obj["id"].(string)
+		if !ok {
+			hasIDs = false
+			break
+		}
+		index[id] = i
+	}
+	if !hasIDs {
+		return next
+	}
+
+	merged := append([]interface{}{}, base...)
+	for _, item := range next {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return next
+		}
+		id, ok := obj["id"].(string)
+		if !ok {
+			return next
+		}
+		if i, exists := index[id]; exists {
+			merged[i] = item
+		} else {
+			merged = append(merged, item)
+			index[id] = len(merged) - 1
+		}
+	}
+	return merged
+}
@@ -0,0 +1,113 @@
+package preferences
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePreferences_DeepestScopeWinsForScalars(t *testing.T) {
+	org := &Preference{Theme: "light", Timezone: "utc", WeekStart: "monday"}
+	team := &Preference{Theme: "dark"}
+	user := &Preference{WeekStart: "sunday"}
+
+	result := MergePreferences(org, user, []*Preference{team})
+
+	assert.Equal(t, "dark", result.Theme)
+	assert.Equal(t, LayerTeam, result.Sources["theme"])
+
+	assert.Equal(t, "utc", result.Timezone)
+	assert.Equal(t, LayerOrg, result.Sources["timezone"])
+
+	assert.Equal(t, "sunday", result.WeekStart)
+	assert.Equal(t, LayerUser, result.Sources["weekStart"])
+}
+
+func TestMergePreferences_NilLayersAreSkipped(t *testing.T) {
+	user := &Preference{Theme: "dark"}
+
+	result := MergePreferences(nil, user, []*Preference{nil})
+
+	assert.Equal(t, "dark", result.Theme)
+	assert.Equal(t, LayerUser, result.Sources["theme"])
+}
+
+func TestMergePreferences_TeamNavbarOverrideDoesNotClobberUserCustomizations(t *testing.T) {
+	team := &Preference{JsonData: mustJSON(t, `{
+		"navbar": [{"id": "explore", "hidden": true}]
+	}`)}
+	user := &Preference{JsonData: mustJSON(t, `{
+		"navbar": [{"id": "alerting", "hidden": true}]
+	}`)}
+
+	result := MergePreferences(nil, user, []*Preference{team})
+
+	navbar := result.JsonData.Get("navbar").MustArray()
+	require.Len(t, navbar, 2)
+
+	byID := map[string]interface{}{}
+	for _, entry := range navbar {
+		obj := entry.(map[string]interface{})
+		byID[obj["id"].(string)] = obj["hidden"]
+	}
+	assert.Equal(t, true, byID["explore"])
+	assert.Equal(t, true, byID["alerting"])
+}
+
+func TestMergeJSONData_DeepestLayerWinsPerKey(t *testing.T) {
+	org := mustJSON(t, `{"queryHistory": "visible", "navbar": [{"id": "a"}]}`)
+	user := mustJSON(t, `{"queryHistory": "hidden"}`)
+
+	merged := mergeJSONData([]*simplejson.Json{org, user})
+
+	assert.Equal(t, "hidden", merged.Get("queryHistory").MustString())
+	assert.Len(t, merged.Get("navbar").MustArray(), 1)
+}
+
+func TestMergeJSONData_NoLayersReturnsEmptyJSON(t *testing.T) {
+	merged := mergeJSONData(nil)
+	m, err := merged.Map()
+	require.NoError(t, err)
+	assert.Empty(t, m)
+}
+
+func TestMergeByID_OverwritesMatchingAppendsRest(t *testing.T) {
+	base := []interface{}{
+		map[string]interface{}{"id": "a", "hidden": false},
+		map[string]interface{}{"id": "b", "hidden": false},
+	}
+	next := []interface{}{
+		map[string]interface{}{"id": "b", "hidden": true},
+		map[string]interface{}{"id": "c", "hidden": true},
+	}
+
+	merged := mergeByID(base, next)
+
+	require.Len(t, merged, 3)
+	byID := map[string]interface{}{}
+	for _, item := range merged {
+		obj := item.(map[string]interface{})
+		byID[obj["id"].(string)] = obj["hidden"]
+	}
+	assert.Equal(t, false, byID["a"])
+	assert.Equal(t, true, byID["b"])
+	assert.Equal(t, true, byID["c"])
+}
+
+func TestMergeByID_FallsBackToNextWhenEntriesHaveNoStableID(t *testing.T) {
+	base := []interface{}{"not-an-object"}
+	next := []interface{}{"replacement"}
+
+	merged := mergeByID(base, next)
+
+	assert.Equal(t, next, merged)
+}
+
+func mustJSON(t *testing.T, raw string) *simplejson.Json {
+	t.Helper()
+	j, err := simplejson.NewJson([]byte(raw))
+	require.NoError(t, err)
+	return j
+}
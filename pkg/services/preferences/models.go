@@ -0,0 +1,63 @@
+package preferences
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ActionTeamsPreferencesWrite gates writes to a team's preference layer,
+// separate from accesscontrol.ActionOrgUsersWrite since a team admin
+// shouldn't need org-wide permissions just to change their team's default
+// theme or nav items.
+const ActionTeamsPreferencesWrite = "teams.preferences:write"
+
+// Layer identifies where a preference value was set. Effective preferences
+// are computed by merging every layer that applies to a user, deepest scope
+// wins.
+type Layer string
+
+const (
+	LayerDefault Layer = "default"
+	LayerOrg     Layer = "org"
+	LayerTeam    Layer = "team"
+	LayerUser    Layer = "user"
+)
+
+// Preference is one layer's worth of stored preferences. Only Org, or Org
+// and TeamId, or Org and UserId are ever set together - it's one row per
+// scope, same as the Grafana preferences table today.
+type Preference struct {
+	Id              int64  `xorm:"pk autoincr 'id'"`
+	OrgId           int64  `xorm:"org_id"`
+	TeamId          int64  `xorm:"team_id"`
+	UserId          int64  `xorm:"user_id"`
+	Theme           string `xorm:"theme"`
+	Timezone        string `xorm:"timezone"`
+	WeekStart       string `xorm:"week_start"`
+	HomeDashboardId int64  `xorm:"home_dashboard_id"`
+
+	JsonData *simplejson.Json `xorm:"json 'json_data'"`
+
+	Created time.Time `xorm:"created"`
+	Updated time.Time `xorm:"updated"`
+}
+
+func (Preference) TableName() string {
+	return "layered_preference"
+}
+
+// EffectivePreferences is what GET /api/preferences/effective returns: the
+// result of merging default -> org -> team(s) -> user for the signed-in
+// user, plus which layer each field ultimately came from so a UI can show
+// "inherited from team X" style hints.
+type EffectivePreferences struct {
+	Theme           string `json:"theme"`
+	Timezone        string `json:"timezone"`
+	WeekStart       string `json:"weekStart"`
+	HomeDashboardId int64  `json:"homeDashboardId"`
+
+	JsonData *simplejson.Json `json:"jsonData,omitempty"`
+
+	Sources map[string]Layer `json:"sources"`
+}
@@ -0,0 +1,117 @@
+package preferences
+
+import (
+	"context"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Store reads and writes a single layer's worth of preferences at a time.
+// Each layer is its own row, so a patch to e.g. team preferences can never
+// race against a concurrent write to org or user preferences.
+type Store interface {
+	GetOrgPreference(ctx context.Context, orgID int64) (*Preference, error)
+	GetTeamPreference(ctx context.Context, orgID, teamID int64) (*Preference, error)
+	GetUserPreference(ctx context.Context, orgID, userID int64) (*Preference, error)
+	ListTeamPreferences(ctx context.Context, orgID int64, teamIDs []int64) ([]*Preference, error)
+
+	SetOrgPreferenceJSONData(ctx context.Context, orgID int64, jsonData *simplejson.Json) error
+	SetTeamPreferenceJSONData(ctx context.Context, orgID, teamID int64, jsonData *simplejson.Json) error
+	SetUserPreferenceJSONData(ctx context.Context, orgID, userID int64, jsonData *simplejson.Json) error
+}
+
+type Service struct {
+	store Store
+}
+
+func ProvideService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// GetEffectivePreferences computes default -> org -> team(s) -> user for a
+// single user, as served by GET /api/preferences/effective.
+func (s *Service) GetEffectivePreferences(ctx context.Context, orgID, userID int64, teamIDs []int64) (*EffectivePreferences, error) {
+	org, err := s.store.GetOrgPreference(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.store.GetUserPreference(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.store.ListTeamPreferences(ctx, orgID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return MergePreferences(org, user, teams), nil
+}
+
+// PatchJSONData applies an RFC 6902 JSON Patch document to a single layer's
+// jsonData, so a caller can flip one nav item or theme field without
+// resending (and racing against other writers of) the whole blob.
+func (s *Service) PatchJSONData(ctx context.Context, layer Layer, orgID, scopeID int64, patchDoc []byte) error {
+	current, err := s.currentJSONData(ctx, layer, orgID, scopeID)
+	if err != nil {
+		return err
+	}
+
+	patch, err := jsonpatch.DecodePatch(patchDoc)
+	if err != nil {
+		return fmt.Errorf("invalid JSON patch document: %w", err)
+	}
+
+	currentBytes, err := current.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	patchedBytes, err := patch.Apply(currentBytes)
+	if err != nil {
+		return fmt.Errorf("failed to apply JSON patch: %w", err)
+	}
+
+	patched, err := simplejson.NewJson(patchedBytes)
+	if err != nil {
+		return err
+	}
+
+	return s.setJSONData(ctx, layer, orgID, scopeID, patched)
+}
+
+func (s *Service) currentJSONData(ctx context.Context, layer Layer, orgID, scopeID int64) (*simplejson.Json, error) {
+	var pref *Preference
+	var err error
+
+	switch layer {
+	case LayerOrg:
+		pref, err = s.store.GetOrgPreference(ctx, orgID)
+	case LayerTeam:
+		pref, err = s.store.GetTeamPreference(ctx, orgID, scopeID)
+	case LayerUser:
+		pref, err = s.store.GetUserPreference(ctx, orgID, scopeID)
+	default:
+		return nil, fmt.Errorf("preferences layer %q is not patchable", layer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pref == nil || pref.JsonData == nil {
+		return simplejson.New(), nil
+	}
+	return pref.JsonData, nil
+}
+
+func (s *Service) setJSONData(ctx context.Context, layer Layer, orgID, scopeID int64, jsonData *simplejson.Json) error {
+	switch layer {
+	case LayerOrg:
+		return s.store.SetOrgPreferenceJSONData(ctx, orgID, jsonData)
+	case LayerTeam:
+		return s.store.SetTeamPreferenceJSONData(ctx, orgID, scopeID, jsonData)
+	case LayerUser:
+		return s.store.SetUserPreferenceJSONData(ctx, orgID, scopeID, jsonData)
+	default:
+		return fmt.Errorf("preferences layer %q is not patchable", layer)
+	}
+}
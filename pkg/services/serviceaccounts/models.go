@@ -8,8 +8,9 @@ import (
 )
 
 var (
-	ScopeAll = "serviceaccounts:*"
-	ScopeID  = accesscontrol.Scope("serviceaccounts", "id", accesscontrol.Parameter(":serviceAccountId"))
+	ScopeAll     = "serviceaccounts:*"
+	ScopeID      = accesscontrol.Scope("serviceaccounts", "id", accesscontrol.Parameter(":serviceAccountId"))
+	ScopeTokenID = accesscontrol.Scope("serviceaccounts", "token", accesscontrol.Parameter(":tokenId"))
 )
 
 const (
@@ -17,6 +18,10 @@ const (
 	ActionWrite  = "serviceaccounts:write"
 	ActionCreate = "serviceaccounts:create"
 	ActionDelete = "serviceaccounts:delete"
+
+	ActionTokenCreate = "serviceaccounts:tokens:create"
+	ActionTokenRevoke = "serviceaccounts:tokens:revoke"
+	ActionTokenRotate = "serviceaccounts:tokens:rotate"
 )
 
 type ServiceAccount struct {
@@ -0,0 +1,55 @@
+package serviceaccounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		granted   []string
+		requested []string
+		wantErr   bool
+	}{
+		{
+			name:      "exact match is allowed",
+			granted:   []string{"dashboards:read:uid:abc"},
+			requested: []string{"dashboards:read:uid:abc"},
+		},
+		{
+			name:      "wildcard grant covers narrower request",
+			granted:   []string{"dashboards:read:uid:*"},
+			requested: []string{"dashboards:read:uid:abc"},
+		},
+		{
+			name:      "global wildcard covers anything",
+			granted:   []string{"*"},
+			requested: []string{"datasources:write:uid:xyz"},
+		},
+		{
+			name:      "wildcard request broader than an exact grant is rejected",
+			granted:   []string{"dashboards:read:uid:abc"},
+			requested: []string{"dashboards:read:uid:*"},
+			wantErr:   true,
+		},
+		{
+			name:      "ungranted resource is rejected",
+			granted:   []string{"dashboards:read:uid:abc"},
+			requested: []string{"datasources:read:uid:abc"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScopes(tt.granted, tt.requested)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
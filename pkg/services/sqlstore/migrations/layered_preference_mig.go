@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// addLayeredPreferenceMigrations creates the storage for the org/team/user
+// preference layers merged by preferences.MergePreferences. It's deliberately
+// a separate table from the existing org-only preferences table rather than
+// an in-place migration of it, so the single-layer legacy endpoints keep
+// working untouched while this layers on top.
+func addLayeredPreferenceMigrations(mg *Migrator) {
+	layeredPreferenceV1 := Table{
+		Name: "layered_preference",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "theme", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "timezone", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "week_start", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "home_dashboard_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "json_data", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "team_id", "user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create layered_preference table v1", NewAddTableMigration(layeredPreferenceV1))
+	mg.AddMigration("add unique index layered_preference.org_id-team_id-user_id",
+		NewAddIndexMigration(layeredPreferenceV1, layeredPreferenceV1.Indices[0]))
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addPluginDashboardRevisionMigrations(mg *Migrator) {
+	pluginDashboardRevisionV1 := Table{
+		Name: "plugin_dashboard_revision",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "plugin_id", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "plugin_version", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "reference", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "dashboard", Type: DB_MediumText, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"plugin_id", "org_id", "dashboard_uid", "plugin_version"}},
+		},
+	}
+
+	mg.AddMigration("create plugin_dashboard_revision table v1", NewAddTableMigration(pluginDashboardRevisionV1))
+	mg.AddMigration("add index plugin_dashboard_revision.plugin_id-org_id-dashboard_uid-plugin_version",
+		NewAddIndexMigration(pluginDashboardRevisionV1, pluginDashboardRevisionV1.Indices[0]))
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addPluginDashboardUpdateLogMigrations(mg *Migrator) {
+	pluginDashboardUpdateLogV1 := Table{
+		Name: "plugin_dashboard_update_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "plugin_id", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "plugin_version", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "revision", Type: DB_BigInt, Nullable: false},
+			{Name: "diff", Type: DB_Text, Nullable: false},
+			{Name: "diff_html", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"plugin_id", "org_id", "dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create plugin_dashboard_update_log table v1", NewAddTableMigration(pluginDashboardUpdateLogV1))
+	mg.AddMigration("add index plugin_dashboard_update_log.plugin_id-org_id-dashboard_uid", NewAddIndexMigration(pluginDashboardUpdateLogV1, pluginDashboardUpdateLogV1.Indices[0]))
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addServiceAccountTokenMigrations(mg *Migrator) {
+	serviceAccountTokenV1 := Table{
+		Name: "service_account_token",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "service_account_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "scopes", Type: DB_Text, Nullable: false},
+			{Name: "hashed_secret", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "expires", Type: DB_DateTime, Nullable: true},
+			{Name: "rotated_from", Type: DB_BigInt, Nullable: true},
+			{Name: "grace_expires", Type: DB_DateTime, Nullable: true},
+			{Name: "revoked", Type: DB_Bool, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"service_account_id"}},
+		},
+	}
+
+	mg.AddMigration("create service_account_token table v1", NewAddTableMigration(serviceAccountTokenV1))
+	mg.AddMigration("add index service_account_token.service_account_id", NewAddIndexMigration(serviceAccountTokenV1, serviceAccountTokenV1.Indices[0]))
+}